@@ -0,0 +1,226 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ABI holds information about a contract's context and available
+// invokable methods. It will allow you to type check function calls and
+// packs data accordingly.
+type ABI struct {
+	Constructor Method
+	Methods     map[string]Method
+	Events      map[string]Event
+	Errors      map[string]Error
+
+	// methodsByID, eventsByID and errorsByID let MethodByID/EventByID/ErrorByID
+	// (and UnpackLog/UnpackCall) route an incoming calldata blob, log or
+	// revert straight to its definition, without every caller building its
+	// own selector table.
+	methodsByID map[[4]byte]*Method
+	eventsByID  map[common.Hash]*Event
+	errorsByID  map[[4]byte]*Error
+}
+
+// JSON returns a parsed ABI interface and error if it failed.
+func JSON(reader io.Reader) (ABI, error) {
+	dec := json.NewDecoder(reader)
+
+	var abi ABI
+	if err := dec.Decode(&abi); err != nil {
+		return ABI{}, err
+	}
+	return abi, nil
+}
+
+// Pack the given method name to conform the ABI. Method call's data
+// will consist of method_id, args0, arg1, ... argN. Method id consists
+// of 4 bytes and arguments are all 32 bytes.
+// Method ids are created from the first 4 bytes of the hash of the
+// methods string signature. (signature = baz(uint32,string32))
+func (abi ABI) Pack(name string, args ...interface{}) ([]byte, error) {
+	// Fetch the ABI of the requested method
+	if name == "" {
+		// constructor
+		arguments, err := abi.Constructor.Inputs.Pack(args...)
+		if err != nil {
+			return nil, err
+		}
+		return arguments, nil
+	}
+	method, exist := abi.Methods[name]
+	if !exist {
+		return nil, fmt.Errorf("method '%s' not found", name)
+	}
+	return method.PackTo(nil, args...)
+}
+
+// Unpack output in v according to the abi specification
+func (abi ABI) Unpack(v interface{}, name string, data []byte) (err error) {
+	if method, ok := abi.Methods[name]; ok {
+		if len(data) == 0 {
+			return fmt.Errorf("abi: unmarshalling empty output")
+		}
+		return method.Outputs.Unpack(v, data)
+	}
+	if event, ok := abi.Events[name]; ok {
+		return event.Inputs.Unpack(v, data)
+	}
+	return fmt.Errorf("abi: could not locate named method or event")
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface
+func (abi *ABI) UnmarshalJSON(data []byte) error {
+	var fields []struct {
+		Type      string
+		Name      string
+		Constant  bool
+		Anonymous bool
+		Inputs    []Argument
+		Outputs   []Argument
+	}
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	abi.Methods = make(map[string]Method)
+	abi.Events = make(map[string]Event)
+	abi.Errors = make(map[string]Error)
+	for _, field := range fields {
+		switch field.Type {
+		case "constructor":
+			abi.Constructor = Method{
+				Inputs: field.Inputs,
+			}
+		// empty defaults to function according to the abi spec
+		case "function", "":
+			abi.Methods[field.Name] = Method{
+				Name:    field.Name,
+				Const:   field.Constant,
+				Inputs:  field.Inputs,
+				Outputs: field.Outputs,
+			}
+		case "event":
+			abi.Events[field.Name] = Event{
+				Name:      field.Name,
+				Anonymous: field.Anonymous,
+				Inputs:    field.Inputs,
+			}
+		case "error":
+			abi.Errors[field.Name] = NewError(field.Name, field.Inputs)
+		}
+	}
+
+	return abi.indexSelectors()
+}
+
+// indexSelectors precomputes the method/event/error selector lookup tables
+// used by MethodByID, EventByID, ErrorByID, UnpackLog and UnpackCall, and
+// reports a collision if two methods (or two events, or two errors) hash to
+// the same selector.
+func (abi *ABI) indexSelectors() error {
+	abi.methodsByID = make(map[[4]byte]*Method, len(abi.Methods))
+	for name := range abi.Methods {
+		// Index by a pointer into the map's own storage, not the range
+		// variable, which is reused across iterations.
+		method := abi.Methods[name]
+		var sel [4]byte
+		copy(sel[:], method.Id())
+		if prev, ok := abi.methodsByID[sel]; ok {
+			return fmt.Errorf("abi: method selector collision: %q and %q both hash to %x", prev.Name, method.Name, sel)
+		}
+		abi.methodsByID[sel] = &method
+	}
+
+	abi.eventsByID = make(map[common.Hash]*Event, len(abi.Events))
+	for name := range abi.Events {
+		event := abi.Events[name]
+		id := event.Id()
+		if prev, ok := abi.eventsByID[id]; ok {
+			return fmt.Errorf("abi: event selector collision: %q and %q both hash to %x", prev.Name, event.Name, id)
+		}
+		abi.eventsByID[id] = &event
+	}
+
+	abi.errorsByID = make(map[[4]byte]*Error, len(abi.Errors))
+	for name := range abi.Errors {
+		errABI := abi.Errors[name]
+		sel := errABI.ID()
+		if prev, ok := abi.errorsByID[sel]; ok {
+			return fmt.Errorf("abi: error selector collision: %q and %q both hash to %x", prev.Name, errABI.Name, sel)
+		}
+		abi.errorsByID[sel] = &errABI
+	}
+	return nil
+}
+
+// MethodByID looks up a Method by its 4-byte selector, as computed by
+// Method.Id.
+func (abi ABI) MethodByID(sigdata [4]byte) (*Method, error) {
+	method, ok := abi.methodsByID[sigdata]
+	if !ok {
+		return nil, fmt.Errorf("abi: no method with id: %#x", sigdata)
+	}
+	return method, nil
+}
+
+// EventByID looks up an Event by the topic0 hash it is identified by, as
+// computed by Event.Id.
+func (abi ABI) EventByID(topic common.Hash) (*Event, error) {
+	event, ok := abi.eventsByID[topic]
+	if !ok {
+		return nil, fmt.Errorf("abi: no event with id: %#x", topic)
+	}
+	return event, nil
+}
+
+// UnpackLog unpacks a log's indexed topics and non-indexed data into out,
+// using log.Topics[0] to look up the matching Event.
+func (abi ABI) UnpackLog(out interface{}, log types.Log) error {
+	if len(log.Topics) == 0 {
+		return fmt.Errorf("abi: anonymous log has no topics to dispatch on")
+	}
+	event, err := abi.EventByID(log.Topics[0])
+	if err != nil {
+		return err
+	}
+	return event.Unpack(out, log.Data, log.Topics)
+}
+
+// UnpackCall unpacks the arguments of a call's calldata into out, using the
+// leading 4-byte selector to look up the matching Method, and returns that
+// method's name.
+func (abi ABI) UnpackCall(out interface{}, calldata []byte) (string, error) {
+	if len(calldata) < 4 {
+		return "", fmt.Errorf("abi: calldata too short to contain a method selector")
+	}
+	var sel [4]byte
+	copy(sel[:], calldata[:4])
+	method, err := abi.MethodByID(sel)
+	if err != nil {
+		return "", err
+	}
+	return method.Name, method.Inputs.Unpack(out, calldata[4:])
+}
@@ -0,0 +1,128 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMethodByIDAndUnpackCall(t *testing.T) {
+	assert := assert.New(t)
+
+	abi, err := JSON(strings.NewReader(transferMethodJSON))
+	assert.NoError(err)
+
+	method := abi.Methods["transfer"]
+	to := common.HexToAddress("0x00Ce0d46d924CC8437c806721496599FC3FFA268")
+	value := big.NewInt(1000000)
+
+	calldata, err := abi.Pack("transfer", to, value)
+	assert.NoError(err)
+
+	var sel [4]byte
+	copy(sel[:], calldata[:4])
+	found, err := abi.MethodByID(sel)
+	assert.NoError(err)
+	assert.Equal(method.Name, found.Name)
+
+	var gotTo common.Address
+	var gotValue *big.Int
+	name, err := abi.UnpackCall(&[]interface{}{&gotTo, &gotValue}, calldata)
+	assert.NoError(err)
+	assert.Equal("transfer", name)
+	assert.Equal(to, gotTo)
+	assert.Equal(value, gotValue)
+
+	if _, err := abi.MethodByID([4]byte{0xde, 0xad, 0xbe, 0xef}); err == nil {
+		t.Fatal("expected an error for an unknown method selector")
+	}
+}
+
+func TestEventByIDAndUnpackLog(t *testing.T) {
+	assert := assert.New(t)
+
+	var e Event
+	assert.NoError(json.Unmarshal(jsonEventTransfer, &e))
+	abi := ABI{Events: map[string]Event{"Transfer": e}}
+	assert.NoError(abi.indexSelectors())
+
+	found, err := abi.EventByID(e.Id())
+	assert.NoError(err)
+	assert.Equal("Transfer", found.Name)
+
+	from := common.HexToAddress("0x00Ce0d46d924CC8437c806721496599FC3FFA268")
+	to := common.HexToAddress("0x0000000000000000000000000000000000dEaD")
+	data, err := hex.DecodeString(transferData1)
+	assert.NoError(err)
+
+	log := types.Log{
+		Topics: []common.Hash{
+			e.Id(),
+			common.BytesToHash(common.LeftPadBytes(from.Bytes(), 32)),
+			common.BytesToHash(common.LeftPadBytes(to.Bytes(), 32)),
+		},
+		Data: data,
+	}
+	var got struct {
+		From, To common.Address
+		Value    *big.Int
+	}
+	assert.NoError(abi.UnpackLog(&got, log))
+	assert.Equal(from, got.From)
+	assert.Equal(to, got.To)
+	assert.Equal(big.NewInt(1000000), got.Value)
+
+	if _, err := abi.EventByID(common.Hash{}); err == nil {
+		t.Fatal("expected an error for an unknown event topic")
+	}
+}
+
+// TestIndexSelectorsDetectsCollision checks that two methods whose
+// signatures hash to the same 4-byte selector are rejected at parse time
+// rather than silently letting the second clobber the first in methodsByID.
+func TestIndexSelectorsDetectsCollision(t *testing.T) {
+	inputs := mustArguments(t, "address", "uint256")
+	var abi ABI
+	abi.Methods = map[string]Method{
+		"a": {Name: "transfer", Inputs: inputs},
+		"b": {Name: "transfer", Inputs: inputs},
+	}
+	if err := abi.indexSelectors(); err == nil {
+		t.Fatal("expected a selector collision error")
+	}
+}
+
+func mustArguments(t *testing.T, typeNames ...string) Arguments {
+	t.Helper()
+	var args Arguments
+	for _, typ := range typeNames {
+		ty, err := NewType(typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		args = append(args, Argument{Type: ty})
+	}
+	return args
+}
@@ -0,0 +1,328 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Argument holds the name of the argument and the corresponding type.
+// Types are used when packing and unpacking data.
+type Argument struct {
+	Name    string
+	Type    Type
+	Indexed bool // indexed is only used by events
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface
+func (argument *Argument) UnmarshalJSON(data []byte) error {
+	var extarg struct {
+		Name    string
+		Type    string
+		Indexed bool
+	}
+	err := json.Unmarshal(data, &extarg)
+	if err != nil {
+		return fmt.Errorf("argument json err: %v", err)
+	}
+
+	argument.Type, err = NewType(extarg.Type)
+	if err != nil {
+		return err
+	}
+	argument.Name = extarg.Name
+	argument.Indexed = extarg.Indexed
+
+	return nil
+}
+
+// Arguments is the slice of Argument that a Method or Event accepts, and
+// implements the pack/unpack logic shared by both.
+type Arguments []Argument
+
+// NonIndexed returns the arguments with indexed arguments filtered out.
+func (arguments Arguments) NonIndexed() Arguments {
+	var ret []Argument
+	for _, arg := range arguments {
+		if !arg.Indexed {
+			ret = append(ret, arg)
+		}
+	}
+	return ret
+}
+
+// isTuple returns true for non-atomic constructs, i.e. if the arguments
+// require a single entry that is itself a tuple of values (more than one
+// non-indexed argument).
+func (arguments Arguments) isTuple() bool {
+	return len(arguments.NonIndexed()) > 1
+}
+
+// Unpack performs the operation hexdata -> Go format
+func (arguments Arguments) Unpack(v interface{}, data []byte) error {
+	if arguments.isTuple() {
+		return arguments.unpackTuple(v, data)
+	}
+	return arguments.unpackAtomic(v, data)
+}
+
+func (arguments Arguments) unpackTuple(v interface{}, output []byte) error {
+	// make sure the passed value is arguments pointer
+	valueOf := reflect.ValueOf(v)
+	if reflect.Ptr != valueOf.Kind() {
+		return fmt.Errorf("abi: Unpack(non-pointer %T)", v)
+	}
+
+	var (
+		value = valueOf.Elem()
+		typ   = value.Type()
+		kind  = value.Kind()
+	)
+
+	// requireUnpackKind is lenient about which of the arguments are indexed:
+	// since indexed event arguments are not part of the unpacked data, a
+	// slice/array destination must still reserve one slot per argument
+	// (including indexed ones, which are simply left untouched below).
+	if err := requireUnpackKind(value, typ, kind, arguments, false); err != nil {
+		return err
+	}
+
+	var abi2struct map[string]string
+	if kind == reflect.Struct {
+		var argNames []string
+		for _, arg := range arguments.NonIndexed() {
+			argNames = append(argNames, arg.Name)
+		}
+		var err error
+		if abi2struct, err = mapArgNamesToStructFields(argNames, value); err != nil {
+			return err
+		}
+	}
+
+	// `i` counts the nonindexed arguments, to correctly compute `data` offset.
+	// `j` counts the number of complex types, also to correct `data` offset.
+	// `pos` is the argument's true position, including indexed arguments, and
+	// is what a slice/array destination must be indexed by: the destination
+	// reserves one slot per argument (see the requireUnpackKind call above),
+	// so an indexed argument occupying an earlier slot must not shift where a
+	// later nonindexed argument is written.
+	i, j := -1, 0
+	for pos, arg := range arguments {
+		if arg.Indexed {
+			// can't read, continue
+			continue
+		}
+		i++
+		marshalledValue, err := toGoType((i+j)*32, arg.Type, output)
+		if err != nil {
+			return err
+		}
+		if arg.Type.T == ArrayTy {
+			// combined index ('i' + 'j') need to be adjusted only by size of array, thus
+			// we need to decrement 'j' because 'i' was incremented
+			j += arg.Type.Size - 1
+		}
+		reflectValue := reflect.ValueOf(marshalledValue)
+
+		switch kind {
+		case reflect.Struct:
+			if err := set(value.FieldByName(abi2struct[arg.Name]), reflectValue, arg); err != nil {
+				return err
+			}
+		case reflect.Slice, reflect.Array:
+			v := value.Index(pos)
+			if err := requireAssignable(v, reflectValue); err != nil {
+				return err
+			}
+			if err := set(v.Elem(), reflectValue, arg); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("abi: cannot unmarshal tuple into %v", typ)
+		}
+	}
+	return nil
+}
+
+// unpackAtomic unpacks ( hexdata -> go ) a single value
+func (arguments Arguments) unpackAtomic(v interface{}, output []byte) error {
+	if len(arguments.NonIndexed()) == 0 {
+		return nil
+	}
+	arg := arguments.NonIndexed()[0]
+	// make sure the passed value is a pointer
+	valueOf := reflect.ValueOf(v)
+	if reflect.Ptr != valueOf.Kind() {
+		return fmt.Errorf("abi: Unpack(non-pointer %T)", v)
+	}
+
+	value := valueOf.Elem()
+
+	marshalledValue, err := toGoType(0, arg.Type, output)
+	if err != nil {
+		return err
+	}
+	reflectValue := reflect.ValueOf(marshalledValue)
+
+	switch value.Kind() {
+	case reflect.Struct:
+		abi2struct, err := mapArgNamesToStructFields([]string{arg.Name}, value)
+		if err != nil {
+			return err
+		}
+		return set(value.FieldByName(abi2struct[arg.Name]), reflectValue, arg)
+	case reflect.Slice, reflect.Array:
+		// A slice/array destination whose type already matches the unpacked
+		// value (e.g. common.Address, [32]byte) is assigned directly; one
+		// whose elements are themselves pointers/interfaces (e.g.
+		// []interface{}) is treated as a container instead: with one slot
+		// per argument (as for an event with indexed arguments) the value
+		// goes to its argument's slot, otherwise to slot 0.
+		if value.Type().AssignableTo(reflectValue.Type()) {
+			return set(value, reflectValue, arg)
+		}
+		slot := 0
+		if value.Len() == len(arguments) {
+			for pos, a := range arguments {
+				if !a.Indexed {
+					slot = pos
+					break
+				}
+			}
+		}
+		dst := value.Index(slot)
+		if err := requireAssignable(dst, reflectValue); err != nil {
+			return err
+		}
+		return set(dst.Elem(), reflectValue, arg)
+	default:
+		return set(value, reflectValue, arg)
+	}
+}
+
+// UnpackValues can be used to unpack ABI-encoded hexdata according to the ABI-specification,
+// without supplying a struct to unpack into. Instead, this method returns a list containing
+// the values. An atomic argument is a list with one element.
+func (arguments Arguments) UnpackValues(data []byte) ([]interface{}, error) {
+	retval := make([]interface{}, 0, len(arguments))
+	j := 0
+	for _, arg := range arguments {
+		if arg.Indexed {
+			continue
+		}
+		marshalledValue, err := toGoType(j*32, arg.Type, data)
+		if err != nil {
+			return nil, err
+		}
+		if arg.Type.T == ArrayTy {
+			j += arg.Type.Size - 1
+		}
+		j++
+		retval = append(retval, marshalledValue)
+	}
+	return retval, nil
+}
+
+// Pack performs the operation Go format -> Hexdata
+func (arguments Arguments) Pack(args ...interface{}) ([]byte, error) {
+	return arguments.PackTo(nil, args...)
+}
+
+// headSize returns the combined size, in bytes, of the fixed-size "head"
+// words that Pack/PackTo write one per argument (32 bytes, or 32*Size for a
+// statically-sized array), before any dynamic "tail" data.
+func (arguments Arguments) headSize() int {
+	size := 0
+	for _, arg := range arguments {
+		if arg.Type.T == ArrayTy {
+			size += 32 * arg.Type.Size
+		} else {
+			size += 32
+		}
+	}
+	return size
+}
+
+// PackTo performs the operation Go format -> Hexdata, appending the result to
+// dst instead of allocating a fresh slice, and writes each argument directly
+// into its region of dst rather than building a separate tail buffer to
+// concatenate on at the end: the head region is reserved up front, dynamic
+// arguments' tails are appended to dst itself (immediately after the head and
+// any earlier tails) as they are packed, and their head word is filled in by
+// indexing back into the already-reserved region. Pre-sizing dst with
+// PackedSize avoids any further reallocation while doing so, which matters
+// when packing many calls back to back (e.g. Multicall batches, log replay).
+func (arguments Arguments) PackTo(dst []byte, args ...interface{}) ([]byte, error) {
+	if len(args) != len(arguments) {
+		return nil, fmt.Errorf("argument count mismatch: %d for %d", len(args), len(arguments))
+	}
+
+	// Reserve the head region in dst up front; each argument's head word(s)
+	// are filled in below as they're packed, in place.
+	start := len(dst)
+	ret := append(dst, make([]byte, arguments.headSize())...)
+
+	headOffset := 0
+	for i, a := range args {
+		input := arguments[i]
+		packed, err := input.Type.pack(reflect.ValueOf(a))
+		if err != nil {
+			return nil, err
+		}
+		headWords := 1
+		if input.Type.T == ArrayTy {
+			headWords = input.Type.Size
+		}
+		if input.Type.requiresLengthPrefix() {
+			// The head word holds the byte offset, relative to the start of
+			// this tuple's head, to where this argument's tail begins; the
+			// tail itself is appended straight to ret, right after the head
+			// and any earlier tails.
+			offset := len(ret) - start
+			copy(ret[start+headOffset:], packNum(reflect.ValueOf(offset)))
+			ret = append(ret, packed...)
+		} else {
+			copy(ret[start+headOffset:], packed)
+		}
+		headOffset += headWords * 32
+	}
+	return ret, nil
+}
+
+// PackedSize returns the number of bytes Pack(args) would produce, without
+// allocating the encoded head. Callers use it to preallocate a buffer for
+// PackTo, e.g. make([]byte, 0, arguments.PackedSize(args...)).
+func (arguments Arguments) PackedSize(args ...interface{}) (int, error) {
+	if len(args) != len(arguments) {
+		return 0, fmt.Errorf("argument count mismatch: %d for %d", len(args), len(arguments))
+	}
+	size := arguments.headSize()
+	for i, a := range args {
+		input := arguments[i]
+		if !input.Type.requiresLengthPrefix() {
+			continue
+		}
+		packed, err := input.Type.pack(reflect.ValueOf(a))
+		if err != nil {
+			return 0, err
+		}
+		size += len(packed)
+	}
+	return size, nil
+}
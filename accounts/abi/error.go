@@ -0,0 +1,166 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Error represents a custom Solidity error, i.e. a named, typed collection of
+// fields that a revert can carry in place of the plain `Error(string)` reason
+// string. It is identified the same way a Method is: by the first four bytes
+// of the keccak256 hash of its string signature.
+type Error struct {
+	Name   string
+	Inputs Arguments
+}
+
+// NewError creates a new Error.
+func NewError(name string, inputs Arguments) Error {
+	return Error{Name: name, Inputs: inputs}
+}
+
+// Sig returns the errors string signature according to the ABI spec.
+//
+// Example
+//
+//     error InsufficientBalance(uint256 available, uint256 required)    =    "InsufficientBalance(uint256,uint256)"
+func (e Error) Sig() string {
+	types := make([]string, len(e.Inputs))
+	for i, input := range e.Inputs {
+		types[i] = input.Type.String()
+	}
+	return fmt.Sprintf("%v(%v)", e.Name, strings.Join(types, ","))
+}
+
+// ID returns the 4-byte selector used to identify the error in a revert's
+// returned data, i.e. the first four bytes of keccak256(Sig()).
+func (e Error) ID() [4]byte {
+	var id [4]byte
+	copy(id[:], crypto.Keccak256([]byte(e.Sig())))
+	return id
+}
+
+func (e Error) String() string {
+	inputs := make([]string, len(e.Inputs))
+	for i, input := range e.Inputs {
+		inputs[i] = fmt.Sprintf("%v %v", input.Name, input.Type)
+	}
+	return fmt.Sprintf("error %v(%v)", e.Name, strings.Join(inputs, ", "))
+}
+
+// Unpack decodes v from the error arguments that follow the 4-byte selector
+// in a revert's returned data.
+func (e Error) Unpack(v interface{}, data []byte) error {
+	if len(data) < 4 {
+		return fmt.Errorf("abi: invalid error data, too short")
+	}
+	if id := e.ID(); !bytes.Equal(data[:4], id[:]) {
+		return fmt.Errorf("abi: error selector mismatch: expected %x, got %x", id, data[:4])
+	}
+	return e.Inputs.Unpack(v, data[4:])
+}
+
+// errorSig/errorID and panicSig/panicID identify the two built-in Solidity
+// revert reasons: the plain `Error(string)` and the `Panic(uint256)` raised by
+// internal checks (assert, overflow, division by zero, ...).
+var (
+	errorSig = "Error(string)"
+	errorID  = crypto.Keccak256([]byte(errorSig))[:4]
+	panicSig = "Panic(uint256)"
+	panicID  = crypto.Keccak256([]byte(panicSig))[:4]
+
+	panicReasons = map[uint64]string{
+		0x00: "generic panic",
+		0x01: "assert(false)",
+		0x11: "arithmetic underflow or overflow",
+		0x12: "division or modulo by zero",
+		0x21: "enum overflow",
+		0x22: "invalid encoded storage byte array accessed",
+		0x31: "out-of-bounds array access; popping on an empty array",
+		0x32: "out-of-bounds access of an array or bytesN",
+		0x41: "out of memory",
+		0x51: "uninitialized function",
+	}
+)
+
+// ErrorByID looks up an Error by its 4-byte selector, as computed by Error.ID,
+// using the lookup table indexSelectors built at JSON-parse time.
+func (abi ABI) ErrorByID(sigdata [4]byte) (Error, error) {
+	errABI, ok := abi.errorsByID[sigdata]
+	if !ok {
+		return Error{}, fmt.Errorf("no error with id: %#x", sigdata)
+	}
+	return *errABI, nil
+}
+
+// UnpackRevert resolves the reason for a contract revert. It recognizes the
+// standard Error(string) and Panic(uint256) encodings and falls back to
+// matching data[:4] against abi.Errors for user-defined errors.
+func (abi ABI) UnpackRevert(data []byte) (string, error) {
+	if len(data) < 4 {
+		return "", fmt.Errorf("invalid data for unpacking")
+	}
+	switch {
+	case bytes.Equal(data[:4], errorID):
+		typ, err := NewType("string")
+		if err != nil {
+			return "", err
+		}
+		vs, err := (Arguments{{Type: typ}}).UnpackValues(data[4:])
+		if err != nil {
+			return "", err
+		}
+		return vs[0].(string), nil
+	case bytes.Equal(data[:4], panicID):
+		typ, err := NewType("uint256")
+		if err != nil {
+			return "", err
+		}
+		vs, err := (Arguments{{Type: typ}}).UnpackValues(data[4:])
+		if err != nil {
+			return "", err
+		}
+		code := vs[0].(*big.Int).Uint64()
+		reason, ok := panicReasons[code]
+		if !ok {
+			reason = "unknown panic code"
+		}
+		return fmt.Sprintf("%v: 0x%x", reason, code), nil
+	default:
+		var sel [4]byte
+		copy(sel[:], data[:4])
+		errABI, err := abi.ErrorByID(sel)
+		if err != nil {
+			return "", fmt.Errorf("unknown revert reason selector: %#x", data[:4])
+		}
+		vs, err := errABI.Inputs.UnpackValues(data[4:])
+		if err != nil {
+			return "", err
+		}
+		args := make([]string, len(vs))
+		for i, v := range vs {
+			args[i] = fmt.Sprintf("%v", v)
+		}
+		return fmt.Sprintf("%s(%s)", errABI.Name, strings.Join(args, ", ")), nil
+	}
+}
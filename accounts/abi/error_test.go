@@ -0,0 +1,112 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var insufficientBalanceErrorJSON = `[{
+	"name": "InsufficientBalance",
+	"type": "error",
+	"inputs": [
+		{ "name": "available", "type": "uint256" },
+		{ "name": "required", "type": "uint256" }
+	]
+}]`
+
+func TestErrorUnpackAndByID(t *testing.T) {
+	assert := assert.New(t)
+
+	abi, err := JSON(strings.NewReader(insufficientBalanceErrorJSON))
+	assert.NoError(err)
+
+	custom := abi.Errors["InsufficientBalance"]
+	assert.Equal("InsufficientBalance(uint256,uint256)", custom.Sig())
+
+	data, err := custom.Inputs.Pack(big.NewInt(1), big.NewInt(2))
+	assert.NoError(err)
+	id := custom.ID()
+	revertData := append(id[:], data...)
+
+	found, err := abi.ErrorByID(id)
+	assert.NoError(err)
+	assert.Equal(custom.Name, found.Name)
+
+	var got struct {
+		Available *big.Int
+		Required  *big.Int
+	}
+	assert.NoError(found.Unpack(&got, revertData))
+	assert.Equal(big.NewInt(1), got.Available)
+	assert.Equal(big.NewInt(2), got.Required)
+
+	if _, err := abi.ErrorByID([4]byte{0xde, 0xad, 0xbe, 0xef}); err == nil {
+		t.Fatal("expected an error for an unknown selector")
+	}
+}
+
+func TestUnpackRevert(t *testing.T) {
+	assert := assert.New(t)
+
+	var abi ABI
+	assert.NoError(abi.indexSelectors())
+
+	typ, err := NewType("string")
+	assert.NoError(err)
+	data, err := (Arguments{{Type: typ}}).Pack("out of gas")
+	assert.NoError(err)
+	reason, err := abi.UnpackRevert(append(append([]byte{}, errorID...), data...))
+	assert.NoError(err)
+	assert.Equal("out of gas", reason)
+
+	typ, err = NewType("uint256")
+	assert.NoError(err)
+	data, err = (Arguments{{Type: typ}}).Pack(big.NewInt(0x11))
+	assert.NoError(err)
+	reason, err = abi.UnpackRevert(append(append([]byte{}, panicID...), data...))
+	assert.NoError(err)
+	assert.Equal("arithmetic underflow or overflow: 0x11", reason)
+
+	if _, err := abi.UnpackRevert([]byte{0x01, 0x02, 0x03, 0x04}); err == nil {
+		t.Fatal("expected an error for an unrecognized revert selector")
+	}
+}
+
+// TestUnpackRevertCustomError checks that UnpackRevert falls back to
+// abi.Errors for a selector that isn't Error(string) or Panic(uint256),
+// formatting the decoded arguments as "<Name>(<args>)".
+func TestUnpackRevertCustomError(t *testing.T) {
+	assert := assert.New(t)
+
+	abi, err := JSON(strings.NewReader(insufficientBalanceErrorJSON))
+	assert.NoError(err)
+
+	custom := abi.Errors["InsufficientBalance"]
+	data, err := custom.Inputs.Pack(big.NewInt(1), big.NewInt(2))
+	assert.NoError(err)
+	id := custom.ID()
+	revertData := append(id[:], data...)
+
+	reason, err := abi.UnpackRevert(revertData)
+	assert.NoError(err)
+	assert.Equal("InsufficientBalance(1, 2)", reason)
+}
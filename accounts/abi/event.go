@@ -0,0 +1,241 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Event is an event potentially triggered by the EVM's LOG mechanism. The Event
+// holds type information (inputs) about the yielded output. Anonymous events
+// don't get the signature canonical representation as the first LOG topic.
+type Event struct {
+	Name      string
+	Anonymous bool
+	Inputs    Arguments
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface
+func (event *Event) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Name      string
+		Anonymous bool
+		Inputs    Arguments
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("event json err: %v", err)
+	}
+	event.Name = raw.Name
+	event.Anonymous = raw.Anonymous
+	event.Inputs = raw.Inputs
+	return nil
+}
+
+func (event Event) String() string {
+	inputs := make([]string, len(event.Inputs))
+	for i, input := range event.Inputs {
+		inputs[i] = fmt.Sprintf("%v %v", input.Name, input.Type)
+		if input.Indexed {
+			inputs[i] = fmt.Sprintf("%v indexed %v", input.Name, input.Type)
+		}
+	}
+	return fmt.Sprintf("event %v(%v)", event.Name, strings.Join(inputs, ", "))
+}
+
+// Id returns the canonical representation of the event's signature used by the
+// abi definition to identify event names and types.
+func (event Event) Id() common.Hash {
+	types := make([]string, len(event.Inputs))
+	i := 0
+	for _, input := range event.Inputs {
+		types[i] = input.Type.String()
+		i++
+	}
+	return common.BytesToHash(crypto.Keccak256([]byte(fmt.Sprintf("%v(%v)", event.Name, strings.Join(types, ",")))))
+}
+
+// Unpack unpacks both the non-indexed data payload and the indexed topics of a
+// log entry into v, which must be a pointer to a struct or a slice/array with
+// one slot per input (see Arguments.Unpack for the non-indexed rules).
+//
+// For a non-anonymous event topics must still hold topics[0], the event
+// signature, as in a log's Topics; Unpack drops it before decoding the
+// indexed arguments. An anonymous event's topics are all indexed arguments.
+func (event Event) Unpack(v interface{}, data []byte, topics []common.Hash) error {
+	if err := event.Inputs.Unpack(v, data); err != nil {
+		return err
+	}
+	hasIndexed := false
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			hasIndexed = true
+			break
+		}
+	}
+	if !hasIndexed {
+		return nil
+	}
+	if !event.Anonymous {
+		if len(topics) == 0 {
+			return fmt.Errorf("abi: topic/field count mismatch")
+		}
+		topics = topics[1:]
+	}
+	return ParseTopics(v, event.Inputs, topics)
+}
+
+// ParseTopics decodes the given topics into v according to the rules of the
+// ABI spec: value types (e.g. address, uintN, intN, bool, fixed bytesN) are
+// read directly from the topic, while reference types (string, bytes,
+// arrays, structs) only have their keccak256 hash available and are
+// therefore surfaced as a common.Hash.
+//
+// inputs must be the full, ordered list of an event's arguments (as in
+// Event.Inputs, with the event signature topic already removed from topics
+// for non-anonymous events); only entries with Indexed set consume one entry
+// from topics, in order. A slice/array destination reserves one slot per
+// entry in inputs (see unpackTuple), so an indexed argument's slot must be
+// addressed by its true position in inputs, not its position among the
+// indexed subset.
+func ParseTopics(v interface{}, inputs Arguments, topics []common.Hash) error {
+	indexedCount := 0
+	for _, arg := range inputs {
+		if arg.Indexed {
+			indexedCount++
+		}
+	}
+	if indexedCount != len(topics) {
+		return fmt.Errorf("abi: topic/field count mismatch. %d topics, %d indexed fields", len(topics), indexedCount)
+	}
+
+	value := reflect.ValueOf(v).Elem()
+	typ := value.Type()
+	kind := value.Kind()
+
+	var abi2struct map[string]string
+	if kind == reflect.Struct {
+		var argNames []string
+		for _, arg := range inputs {
+			if arg.Indexed {
+				argNames = append(argNames, arg.Name)
+			}
+		}
+		var err error
+		if abi2struct, err = mapArgNamesToStructFields(argNames, value); err != nil {
+			return err
+		}
+	}
+
+	topicIdx := 0
+	for pos, arg := range inputs {
+		if !arg.Indexed {
+			continue
+		}
+		reflectValue, err := parseTopicValue(arg.Type, topics[topicIdx])
+		topicIdx++
+		if err != nil {
+			return err
+		}
+
+		switch kind {
+		case reflect.Struct:
+			if err := set(value.FieldByName(abi2struct[arg.Name]), reflectValue, arg); err != nil {
+				return err
+			}
+		case reflect.Slice, reflect.Array:
+			dst := value.Index(pos)
+			if err := requireAssignable(dst, reflectValue); err != nil {
+				return err
+			}
+			if err := set(dst.Elem(), reflectValue, arg); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("abi: cannot unmarshal topics into %v", typ)
+		}
+	}
+	return nil
+}
+
+// parseTopicValue decodes a single indexed argument out of its 32-byte topic.
+func parseTopicValue(t Type, topic common.Hash) (reflect.Value, error) {
+	switch t.T {
+	case StringTy, BytesTy, SliceTy, ArrayTy, FixedPointTy:
+		// reference types are hashed; the original value is unrecoverable from
+		// the topic, so the caller gets the raw hash instead.
+		return reflect.ValueOf(topic), nil
+	default:
+		value, err := toGoType(0, t, topic.Bytes())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(value), nil
+	}
+}
+
+// MakeTopics converts a filter query into a filter topic set. Each position in
+// query corresponds to one indexed event argument; multiple values at the same
+// position are OR'd together, mirroring filters.FilterQuery's Topics field.
+func MakeTopics(query ...[]interface{}) ([][]common.Hash, error) {
+	topics := make([][]common.Hash, len(query))
+	for i, values := range query {
+		for _, value := range values {
+			var topic common.Hash
+
+			// convert the topic value to a hash
+			switch v := value.(type) {
+			case common.Hash:
+				copy(topic[:], v[:])
+			case common.Address:
+				copy(topic[common.HashLength-common.AddressLength:], v[:])
+			case *big.Int:
+				blob := v.Bytes()
+				copy(topic[common.HashLength-len(blob):], blob)
+			case bool:
+				if v {
+					topic[common.HashLength-1] = 1
+				}
+			case int8, int16, int32, int64:
+				blob := U256(big.NewInt(reflect.ValueOf(v).Int()))
+				copy(topic[:], blob)
+			case uint8, uint16, uint32, uint64:
+				blob := U256(new(big.Int).SetUint64(reflect.ValueOf(v).Uint()))
+				copy(topic[:], blob)
+			case string:
+				hash := crypto.Keccak256Hash([]byte(v))
+				copy(topic[:], hash[:])
+			case []byte:
+				hash := crypto.Keccak256Hash(v)
+				copy(topic[:], hash[:])
+			default:
+				// todo(rjl493456442) according solidity documentation, indexed event
+				// parameters that are not value types (i.e. arrays and structs) are not
+				// stored directly but instead a keccak256-hash of an encoding is stored.
+				return nil, fmt.Errorf("unsupported indexed type: %T", value)
+			}
+			topics[i] = append(topics[i], topic)
+		}
+	}
+	return topics, nil
+}
@@ -110,6 +110,65 @@ func TestEventId(t *testing.T) {
 	}
 }
 
+// Approval(address indexed owner, address indexed spender, uint256 value, uint256 deadline)
+var jsonEventApproval = []byte(`{
+  "anonymous": false,
+  "inputs": [{
+      "indexed": true, "name": "owner", "type": "address"
+    }, {
+      "indexed": true, "name": "spender", "type": "address"
+    }, {
+      "indexed": false, "name": "value", "type": "uint256"
+    }, {
+      "indexed": false, "name": "deadline", "type": "uint256"
+  }],
+  "name": "Approval",
+  "type": "event"
+}`)
+
+// TestEventUnpackIndexed is a regression test for a pair of indexed args
+// followed by two or more non-indexed args: unpacking into a slice must not
+// let the indexed-only and non-indexed-only position counters collide and
+// overwrite each other's slots.
+func TestEventUnpackIndexed(t *testing.T) {
+	assert := assert.New(t)
+
+	var e Event
+	assert.NoError(json.Unmarshal(jsonEventApproval, &e))
+
+	owner := common.HexToAddress("0x00Ce0d46d924CC8437c806721496599FC3FFA268")
+	spender := common.HexToAddress("0x0000000000000000000000000000000000dEaD")
+	value := big.NewInt(1000000)
+	deadline := big.NewInt(1700000000)
+
+	topics := []common.Hash{
+		{}, // event signature topic, ignored by Unpack for non-anonymous events
+		common.BytesToHash(common.LeftPadBytes(owner.Bytes(), 32)),
+		common.BytesToHash(common.LeftPadBytes(spender.Bytes(), 32)),
+	}
+	data, err := (Arguments{e.Inputs[2], e.Inputs[3]}).Pack(value, deadline)
+	assert.NoError(err)
+
+	gotOwner, gotSpender := new(common.Address), new(common.Address)
+	gotValue, gotDeadline := new(big.Int), new(big.Int)
+	got := []interface{}{gotOwner, gotSpender, &gotValue, &gotDeadline}
+	assert.NoError(e.Unpack(&got, data, topics))
+	assert.Equal(&owner, gotOwner)
+	assert.Equal(&spender, gotSpender)
+	assert.Equal(value, gotValue)
+	assert.Equal(deadline, gotDeadline)
+
+	type ApprovalEvent struct {
+		Owner    common.Address
+		Spender  common.Address
+		Value    *big.Int
+		Deadline *big.Int
+	}
+	var gotStruct ApprovalEvent
+	assert.NoError(e.Unpack(&gotStruct, data, topics))
+	assert.Equal(ApprovalEvent{owner, spender, value, deadline}, gotStruct)
+}
+
 func TestEventTupleUnpack(t *testing.T) {
 
 	type EventTransfer struct {
@@ -213,8 +272,11 @@ func TestEventTupleUnpack(t *testing.T) {
 		"Can not unpack Pledge event into map",
 	}, {
 		staticArrayEventData,
-		&[3]interface{}{&[3]*big.Int{}, new(string)},
+		// index 0 is reserved for the indexed "a" argument, which Unpack
+		// leaves untouched; only b and c (nonindexed) land in 1 and 2.
+		&[3]interface{}{nil, &[3]*big.Int{}, new(string)},
 		&[3]interface{}{
+			nil,
 			&[3]*big.Int{big.NewInt(4), big.NewInt(5), big.NewInt(6)},
 			strPtr("abc")},
 		jsonEventStaticArray,
@@ -0,0 +1,232 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// transferMethodJSON mirrors the ERC-20 transfer(address,uint256) signature;
+// see Arguments.PackTo for why PackTo/PackedSize exist.
+var transferMethodJSON = `[{
+	"name": "transfer",
+	"type": "function",
+	"inputs": [
+		{ "name": "to", "type": "address" },
+		{ "name": "value", "type": "uint256" }
+	],
+	"outputs": [{ "name": "", "type": "bool" }]
+}]`
+
+// relayMethodJSON has a dynamic bytes argument, to exercise PackTo's tail
+// region: calldata built for batching (e.g. Multicall aggregate calls) is
+// practically always dynamic, unlike transfer(address,uint256).
+var relayMethodJSON = `[{
+	"name": "relay",
+	"type": "function",
+	"inputs": [
+		{ "name": "to", "type": "address" },
+		{ "name": "value", "type": "uint256" },
+		{ "name": "data", "type": "bytes" }
+	],
+	"outputs": [{ "name": "", "type": "bool" }]
+}]`
+
+func transferMethod(t testing.TB) Method {
+	abi, err := JSON(strings.NewReader(transferMethodJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return abi.Methods["transfer"]
+}
+
+func relayMethod(t testing.TB) Method {
+	abi, err := JSON(strings.NewReader(relayMethodJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return abi.Methods["relay"]
+}
+
+func TestMethodPackTo(t *testing.T) {
+	assert := assert.New(t)
+	method := transferMethod(t)
+
+	to := common.HexToAddress("0x00Ce0d46d924CC8437c806721496599FC3FFA268")
+	value := big.NewInt(1000000)
+
+	want, err := method.PackTo(nil, to, value)
+	assert.NoError(err)
+
+	size, err := method.PackedSize(to, value)
+	assert.NoError(err)
+	assert.Equal(len(want), size)
+
+	// PackTo must append, not overwrite, whatever dst already holds.
+	prefix := []byte{0xde, 0xad, 0xbe, 0xef}
+	got, err := method.PackTo(append([]byte{}, prefix...), to, value)
+	assert.NoError(err)
+	assert.Equal(append(prefix, want...), got)
+}
+
+// TestMethodPackToDynamic is TestMethodPackTo's counterpart for an argument
+// list with a dynamic tail (bytes), the case PackTo's tail-writing logic
+// actually needs to get right.
+func TestMethodPackToDynamic(t *testing.T) {
+	assert := assert.New(t)
+	method := relayMethod(t)
+
+	to := common.HexToAddress("0x00Ce0d46d924CC8437c806721496599FC3FFA268")
+	value := big.NewInt(1000000)
+	data := []byte("hello, multicall")
+
+	want, err := method.PackTo(nil, to, value, data)
+	assert.NoError(err)
+
+	size, err := method.PackedSize(to, value, data)
+	assert.NoError(err)
+	assert.Equal(len(want), size)
+
+	prefix := []byte{0xde, 0xad, 0xbe, 0xef}
+	got, err := method.PackTo(append([]byte{}, prefix...), to, value, data)
+	assert.NoError(err)
+	assert.Equal(append(prefix, want...), got)
+
+	var gotTo common.Address
+	var gotValue *big.Int
+	var gotData []byte
+	assert.NoError(method.Inputs.Unpack(&[]interface{}{&gotTo, &gotValue, &gotData}, want[4:]))
+	assert.Equal(to, gotTo)
+	assert.Equal(value, gotValue)
+	assert.Equal(data, gotData)
+}
+
+func BenchmarkMethodPack(b *testing.B) {
+	method := transferMethod(b)
+	to := common.HexToAddress("0x00Ce0d46d924CC8437c806721496599FC3FFA268")
+	value := big.NewInt(1000000)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := method.PackTo(nil, to, value); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkMethodPackToPreallocated demonstrates the allocation win PackTo
+// offers when a caller batches many calls and can size the destination
+// buffer once via PackedSize; see Arguments.PackTo.
+func BenchmarkMethodPackToPreallocated(b *testing.B) {
+	method := transferMethod(b)
+	to := common.HexToAddress("0x00Ce0d46d924CC8437c806721496599FC3FFA268")
+	value := big.NewInt(1000000)
+
+	size, err := method.PackedSize(to, value)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dst := make([]byte, 0, size)
+		if _, err := method.PackTo(dst, to, value); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkMethodPackToPreallocatedDynamic is BenchmarkMethodPackToPreallocated's
+// counterpart for a call with a dynamic (bytes) tail, the shape real
+// Multicall aggregate calldata actually has.
+func BenchmarkMethodPackToPreallocatedDynamic(b *testing.B) {
+	method := relayMethod(b)
+	to := common.HexToAddress("0x00Ce0d46d924CC8437c806721496599FC3FFA268")
+	value := big.NewInt(1000000)
+	data := []byte("hello, multicall")
+
+	size, err := method.PackedSize(to, value, data)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dst := make([]byte, 0, size)
+		if _, err := method.PackTo(dst, to, value, data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkMethodPackToBatch packs a whole batch of calls into one shared
+// dst, pre-sized once for the entire batch via PackedSize; this is the
+// actual "Multicall aggregate calls"/log-replay scenario PackTo's allocation
+// avoidance targets. BenchmarkMethodPackToPreallocated(Dynamic) re-allocate
+// dst on every b.N iteration and so never show this: each call there is
+// independent, not part of a shared buffer.
+func BenchmarkMethodPackToBatch(b *testing.B) {
+	const batchSize = 32
+	method := transferMethod(b)
+	to := common.HexToAddress("0x00Ce0d46d924CC8437c806721496599FC3FFA268")
+	value := big.NewInt(1000000)
+
+	callSize, err := method.PackedSize(to, value)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dst := make([]byte, 0, callSize*batchSize)
+		for j := 0; j < batchSize; j++ {
+			if dst, err = method.PackTo(dst, to, value); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkMethodPackToBatchDynamic is BenchmarkMethodPackToBatch's
+// counterpart for calls with a dynamic (bytes) tail.
+func BenchmarkMethodPackToBatchDynamic(b *testing.B) {
+	const batchSize = 32
+	method := relayMethod(b)
+	to := common.HexToAddress("0x00Ce0d46d924CC8437c806721496599FC3FFA268")
+	value := big.NewInt(1000000)
+	data := []byte("hello, multicall")
+
+	callSize, err := method.PackedSize(to, value, data)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dst := make([]byte, 0, callSize*batchSize)
+		for j := 0; j < batchSize; j++ {
+			if dst, err = method.PackTo(dst, to, value, data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"math/big"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+var (
+	bigT      = reflect.TypeOf(&big.Int{})
+	uint8T    = reflect.TypeOf(uint8(0))
+	uint16T   = reflect.TypeOf(uint16(0))
+	uint32T   = reflect.TypeOf(uint32(0))
+	uint64T   = reflect.TypeOf(uint64(0))
+	int8T     = reflect.TypeOf(int8(0))
+	int16T    = reflect.TypeOf(int16(0))
+	int32T    = reflect.TypeOf(int32(0))
+	int64T    = reflect.TypeOf(int64(0))
+)
+
+// packNum packs the given number (using the reflect value) and will cast it
+// to the given type, rightfully to their bit sizes and following the ABI spec
+// (left padded to 32 bytes).
+func packNum(value reflect.Value) []byte {
+	switch kind := value.Kind(); kind {
+	case reflect.Slice:
+		return value.Bytes()
+	case reflect.Ptr:
+		return U256(value.Interface().(*big.Int))
+	default:
+		b := new(big.Int).SetInt64(value.Convert(reflect.TypeOf(int64(0))).Int())
+		return U256(b)
+	}
+}
+
+// U256 converts a big.Int into a 256-bit EVM number, which is left-padded
+// and two's-complemented for negative values.
+func U256(n *big.Int) []byte {
+	return common.LeftPadBytes(math.U256(n).Bytes(), 32)
+}
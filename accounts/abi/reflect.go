@@ -0,0 +1,208 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	addressT = reflect.TypeOf(common.Address{})
+	hashT    = reflect.TypeOf(common.Hash{})
+)
+
+// indirect recursively dereferences the value until it either gets the value
+// or finds a big.Int
+func indirect(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Ptr && v.Type() != bigT {
+		return indirect(v.Elem())
+	}
+	return v
+}
+
+// reflectIntKindAndType returns the reflect using the given size and
+// unsignedness.
+func reflectIntKindAndType(unsigned bool, size int) (reflect.Kind, reflect.Type) {
+	switch size {
+	case 8:
+		if unsigned {
+			return reflect.Uint8, uint8T
+		}
+		return reflect.Int8, int8T
+	case 16:
+		if unsigned {
+			return reflect.Uint16, uint16T
+		}
+		return reflect.Int16, int16T
+	case 32, 64:
+		if size == 32 {
+			if unsigned {
+				return reflect.Uint32, uint32T
+			}
+			return reflect.Int32, int32T
+		}
+		if unsigned {
+			return reflect.Uint64, uint64T
+		}
+		return reflect.Int64, int64T
+	}
+	return reflect.Ptr, bigT
+}
+
+// mustArrayToByteSlice creates a new byte slice with the exact same size as
+// value and copies the bytes in value to the new slice.
+func mustArrayToByteSlice(value reflect.Value) reflect.Value {
+	slice := reflect.MakeSlice(reflect.TypeOf([]byte{}), value.Len(), value.Len())
+	reflect.Copy(slice, value)
+	return slice
+}
+
+// set attempts to assign src to dst by either direct assignment, or otherwise
+// casting src to dst's type.
+func set(dst, src reflect.Value, output Argument) error {
+	dstType := dst.Type()
+	srcType := src.Type()
+	switch {
+	case dstType.AssignableTo(srcType):
+		dst.Set(src)
+	case dstType.Kind() == reflect.Interface:
+		dst.Set(src)
+	case dstType.Kind() == reflect.Ptr:
+		return set(dst.Elem(), src, output)
+	default:
+		return fmt.Errorf("abi: cannot unmarshal %v in to %v", src.Type(), dst.Type())
+	}
+	return nil
+}
+
+// requireAssignable assures that `dest` is either a pointer or an interface.
+func requireAssignable(dst, src reflect.Value) error {
+	if dst.Kind() != reflect.Ptr && dst.Kind() != reflect.Interface {
+		return fmt.Errorf("abi: cannot unmarshal %v into %v", src.Type(), dst.Type())
+	}
+	return nil
+}
+
+// capitalise makes the first character of a string upper case, also removing any
+// prefixing underscores from the variable names.
+func capitalise(input string) string {
+	for len(input) > 0 && input[0] == '_' {
+		input = input[1:]
+	}
+	if len(input) == 0 {
+		return ""
+	}
+	return strings.ToUpper(input[:1]) + input[1:]
+}
+
+// mapArgNamesToStructFields maps a slice of ABI argument names to struct
+// fields. The mapping is done as follows:
+//
+// Firstly, it's determined if the name of a field matches to a struct field
+// via a field tag (`abi:"fieldName"`), if so this pairing is added and the
+// arg name is marked as used.
+//
+// Secondly, any unpaired arg names are matched against struct fields by
+// capitalising the arg name and checking for an exact match. It is an error
+// for an arg name to be claimed by more than one struct field (whether
+// through a tag or through the name heuristic), and an error for an arg name
+// to have no corresponding destination field at all.
+func mapArgNamesToStructFields(argNames []string, value reflect.Value) (map[string]string, error) {
+	typ := value.Type()
+
+	abi2struct := make(map[string]string)
+	struct2abi := make(map[string]string)
+
+	for i := 0; i < typ.NumField(); i++ {
+		structFieldName := typ.Field(i).Name
+
+		// skip unexported struct fields.
+		if structFieldName[0:1] != strings.ToUpper(structFieldName[0:1]) {
+			continue
+		}
+		// skip fields that have no abi:"" tag.
+		tagName, ok := typ.Field(i).Tag.Lookup("abi")
+		if !ok {
+			continue
+		}
+		if tagName == "" {
+			return nil, fmt.Errorf("struct: abi tag in '%s' is empty", structFieldName)
+		}
+		// check which argument field matches with the abi tag.
+		found := false
+		for _, argName := range argNames {
+			if argName == tagName {
+				if abi2struct[argName] != "" {
+					return nil, fmt.Errorf("struct: abi tag in '%s' already mapped", structFieldName)
+				}
+				abi2struct[argName] = structFieldName
+				struct2abi[structFieldName] = argName
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("struct: abi tag '%s' defined but not found in abi", tagName)
+		}
+	}
+
+	// Second round: pair every argument that wasn't claimed by a tag against
+	// the capitalised form of its name.
+	for _, argName := range argNames {
+		structFieldName := capitalise(argName)
+		if abi2struct[argName] != "" {
+			// Already claimed by a tag: an unclaimed field that the name
+			// heuristic would also pair with it makes the mapping ambiguous.
+			if abi2struct[argName] != structFieldName && struct2abi[structFieldName] == "" && value.FieldByName(structFieldName).IsValid() {
+				return nil, fmt.Errorf("abi: multiple struct fields mapping to the same output '%s'", argName)
+			}
+			continue
+		}
+
+		if structFieldName == "" {
+			return nil, fmt.Errorf("abi: purely underscored output cannot unpack to struct")
+		}
+		if struct2abi[structFieldName] != "" {
+			return nil, fmt.Errorf("abi: multiple outputs mapping to the same struct field '%s'", structFieldName)
+		}
+		if !value.FieldByName(structFieldName).IsValid() {
+			return nil, fmt.Errorf("abi: could not locate destination field %s for output %s", structFieldName, argName)
+		}
+		abi2struct[argName] = structFieldName
+		struct2abi[structFieldName] = argName
+	}
+	return abi2struct, nil
+}
+
+// requireUnpackKind verifies that the type of v has a deterministic number of
+// of elements, i.e. it cannot be a slice with a variable number of elements,
+// when the arguments are a tuple being unpacked into it.
+func requireUnpackKind(v reflect.Value, t reflect.Type, k reflect.Kind, args Arguments, isTupleReturn bool) error {
+	switch k {
+	case reflect.Struct:
+	case reflect.Slice, reflect.Array:
+		if len(args) != v.Len() {
+			return fmt.Errorf("abi: insufficient number of elements in the list/array for unpack, want %d, got %d", len(args), v.Len())
+		}
+	default:
+		return fmt.Errorf("abi: cannot unmarshal tuple into %v", t)
+	}
+	return nil
+}
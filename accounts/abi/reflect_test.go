@@ -0,0 +1,59 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMapArgNamesToStructFields(t *testing.T) {
+	// Struct field tag overrides the capitalise heuristic: "from" maps to
+	// Sender, not the nonexistent "From" field.
+	type taggedStruct struct {
+		Sender string `abi:"from"`
+		To     string
+	}
+	var tagged taggedStruct
+	mapping, err := mapArgNamesToStructFields([]string{"from", "to"}, reflect.ValueOf(&tagged).Elem())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mapping["from"] != "Sender" || mapping["to"] != "To" {
+		t.Fatalf("unexpected mapping: %v", mapping)
+	}
+
+	// Two distinct arg names both claimed by the capitalise heuristic onto
+	// the same field name is an ambiguous mapping.
+	type ambiguousStruct struct {
+		Value string
+	}
+	var ambiguous ambiguousStruct
+	if _, err := mapArgNamesToStructFields([]string{"value", "Value"}, reflect.ValueOf(&ambiguous).Elem()); err == nil {
+		t.Fatal("expected an error for an ambiguous struct field mapping")
+	}
+
+	// An arg name with no corresponding field, tagged or capitalised, is an
+	// error rather than a silently dropped value.
+	type incompleteStruct struct {
+		To string
+	}
+	var incomplete incompleteStruct
+	if _, err := mapArgNamesToStructFields([]string{"from", "to"}, reflect.ValueOf(&incomplete).Elem()); err == nil {
+		t.Fatal("expected an error for a missing destination field")
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abi
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestPackArrayOfDynamicTypeRejected is a regression test: a fixed-size array
+// of a dynamic element type (e.g. string[2]) can't be packed by naive
+// per-element concatenation, so Pack must reject it rather than silently
+// emitting corrupted calldata.
+func TestPackArrayOfDynamicTypeRejected(t *testing.T) {
+	typ, err := NewType("string[2]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = (Arguments{{Type: typ}}).Pack([2]string{"hello world this is long", "bye"})
+	if err == nil {
+		t.Fatal("expected an error packing an array of a dynamic type")
+	}
+	if !strings.Contains(err.Error(), "dynamic type") {
+		t.Fatalf("expected a dynamic-type error, got: %v", err)
+	}
+}